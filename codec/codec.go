@@ -0,0 +1,10 @@
+// Package codec defines the encode/decode abstraction used by the encoded
+// NATS connection to move typed Go values over the wire instead of raw
+// []byte payloads.
+package codec
+
+// Codec encodes a Go value to bytes and decodes bytes back into a Go value.
+type Codec interface {
+	Encode(v any) ([]byte, error)
+	Decode(data []byte, v any) error
+}
@@ -0,0 +1,40 @@
+package codec
+
+import "testing"
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	type payload struct {
+		ID   string
+		Body string
+	}
+
+	c := JSONCodec{}
+	in := payload{ID: "1", Body: "hello"}
+
+	data, err := c.Encode(in)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var out payload
+	if err := c.Decode(data, &out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if out != in {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out, in)
+	}
+}
+
+func TestProtobufCodecRejectsNonProtoMessage(t *testing.T) {
+	c := ProtobufCodec{}
+
+	if _, err := c.Encode("not a proto.Message"); err == nil {
+		t.Fatal("Encode: expected error for non-proto.Message value, got nil")
+	}
+
+	var dst string
+	if err := c.Decode([]byte("x"), &dst); err == nil {
+		t.Fatal("Decode: expected error for non-proto.Message value, got nil")
+	}
+}
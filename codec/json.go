@@ -0,0 +1,14 @@
+package codec
+
+import "encoding/json"
+
+// JSONCodec encodes values as JSON.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Decode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
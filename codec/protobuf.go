@@ -0,0 +1,26 @@
+package codec
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtobufCodec encodes values that implement proto.Message.
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) Encode(v any) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("codec: %T does not implement proto.Message", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (ProtobufCodec) Decode(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("codec: %T does not implement proto.Message", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
@@ -1,20 +1,130 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/wazwki/HTTPServerWithNATS/codec"
+	"github.com/wazwki/HTTPServerWithNATS/encoded"
+	"github.com/wazwki/HTTPServerWithNATS/middleware"
+	"github.com/wazwki/HTTPServerWithNATS/models"
+	"github.com/wazwki/HTTPServerWithNATS/natsconn"
+	"github.com/wazwki/HTTPServerWithNATS/natsrpc"
+	"github.com/wazwki/HTTPServerWithNATS/plugins"
+	"github.com/wazwki/HTTPServerWithNATS/stream"
 )
 
+// metricsAddr serves /metrics so the nats_* counters registered by
+// middleware.Metrics can be scraped.
+const metricsAddr = ":9090"
+
 func main() {
-	nc, err := nats.Connect(nats.DefaultURL)
+	pluginPath := flag.String("plugin", "", "path to a .wasm module to dispatch incoming updates to")
+	pluginHandler := flag.String("handler", "on_message", "exported guest function to invoke")
+	ackWait := flag.Duration("ack-wait", 0, "how long JetStream waits for an Ack before redelivering (0 keeps the stream package default)")
+	maxInFlight := flag.Int("max-in-flight", 0, "max unacked messages the durable consumer may have outstanding (0 keeps the stream package default)")
+	replayFrom := flag.String("replay-from", "", "RFC3339 timestamp to replay the stream from when recovering after an outage (empty replays from the stream's start)")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		if err := http.ListenAndServe(metricsAddr, mux); err != nil {
+			log.Printf("metrics server: %v", err)
+		}
+	}()
+
+	nc, err := natsconn.Connect(nats.DefaultURL, natsconn.DefaultConfig(), logger)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer nc.Close()
 
-	_, err = nc.Subscribe("updates", func(msg *nats.Msg) {
-		log.Printf("Получено сообщение: %s", string(msg.Data))
+	if *pluginPath != "" {
+		ctx := context.Background()
+		registry, err := plugins.NewRegistry(ctx, nc, *pluginPath, *pluginHandler)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go registry.Watch(ctx)
+
+		_, err = nc.Subscribe("updates.*", func(msg *nats.Msg) {
+			if err := registry.Dispatch(ctx, msg); err != nil {
+				log.Printf("plugins: dispatch failed: %v", err)
+			}
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		select {}
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := stream.EnsureStream(js); err != nil {
+		log.Fatal(err)
+	}
+
+	consumerConfig := stream.DefaultConfig("consumer-service")
+	if *ackWait > 0 {
+		consumerConfig.AckWait = *ackWait
+	}
+	if *maxInFlight > 0 {
+		consumerConfig.MaxInFlight = *maxInFlight
+	}
+	if *replayFrom != "" {
+		t, err := time.Parse(time.RFC3339, *replayFrom)
+		if err != nil {
+			log.Fatalf("invalid --replay-from: %v", err)
+		}
+		consumerConfig.ReplayFrom = t
+	}
+
+	consumer, err := stream.NewDurableConsumer(js, "updates.*", consumerConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Best-effort mirror of the durable stream above, for anything that only
+	// cares about the latest value and would rather not pay for replay.
+	ec := encoded.NewEncodedConn(nc, codec.JSONCodec{})
+	_, err = ec.SubscribeTyped("live.updates", func(u models.Update) {
+		log.Printf("Живое обновление: %s", u.Body)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	handle := middleware.ChainErr(func(ctx context.Context, msg *nats.Msg) error {
+		var u models.Update
+		if err := (codec.JSONCodec{}).Decode(msg.Data, &u); err != nil {
+			return fmt.Errorf("decode %q: %w", msg.Subject, err)
+		}
+		log.Printf("Получено сообщение: %s", u.Body)
+		return nil
+	}, middleware.Recovery(), middleware.Metrics(), middleware.Tracing())
+
+	go consumer.Run(func(msg *nats.Msg) error {
+		return handle(context.Background(), msg)
+	})
+
+	server := natsrpc.NewServer(nc)
+	err = server.Handle("rpc.updates", func(ctx context.Context, req []byte) ([]byte, error) {
+		log.Printf("Обработан RPC-запрос: %s", string(req))
+		return []byte("ok"), nil
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,71 @@
+// Package encoded wraps a *nats.Conn with a codec.Codec so callers can
+// publish and subscribe with typed Go values instead of raw []byte, mirroring
+// the EncodedConn pattern from the nats.go ecosystem.
+package encoded
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/nats-io/nats.go"
+	"github.com/wazwki/HTTPServerWithNATS/codec"
+)
+
+// EncodedConn pairs a NATS connection with a Codec for typed pub/sub.
+type EncodedConn struct {
+	nc    *nats.Conn
+	codec codec.Codec
+}
+
+// NewEncodedConn wraps nc, encoding and decoding messages with c.
+func NewEncodedConn(nc *nats.Conn, c codec.Codec) *EncodedConn {
+	return &EncodedConn{nc: nc, codec: c}
+}
+
+// PublishTyped encodes v with the configured codec and publishes it on subject.
+func (ec *EncodedConn) PublishTyped(subject string, v any) error {
+	data, err := ec.codec.Encode(v)
+	if err != nil {
+		return fmt.Errorf("encoded: encode for %q: %w", subject, err)
+	}
+	return ec.nc.Publish(subject, data)
+}
+
+// SubscribeTyped subscribes to subject and invokes handler with the decoded
+// value. handler must be a func(T) where T is a concrete struct type; the
+// message is decoded into a new *T via reflection before the call.
+func (ec *EncodedConn) SubscribeTyped(subject string, handler any) (*nats.Subscription, error) {
+	dispatch, err := newTypedDispatcher(ec.codec, handler)
+	if err != nil {
+		return nil, err
+	}
+	return ec.nc.Subscribe(subject, func(msg *nats.Msg) {
+		_ = dispatch(msg.Data)
+	})
+}
+
+// typedDispatcher decodes raw bytes into handler's single argument type and
+// invokes handler, reporting a decode failure instead of calling it.
+type typedDispatcher func(data []byte) error
+
+// newTypedDispatcher validates that handler is a func(T) and returns a
+// dispatcher that decodes with c before calling it. Split out from
+// SubscribeTyped so the reflection logic can be unit tested without a live
+// NATS connection.
+func newTypedDispatcher(c codec.Codec, handler any) (typedDispatcher, error) {
+	hv := reflect.ValueOf(handler)
+	ht := hv.Type()
+	if ht.Kind() != reflect.Func || ht.NumIn() != 1 {
+		return nil, fmt.Errorf("encoded: handler must be a func(T), got %T", handler)
+	}
+	argType := ht.In(0)
+
+	return func(data []byte) error {
+		argPtr := reflect.New(argType)
+		if err := c.Decode(data, argPtr.Interface()); err != nil {
+			return fmt.Errorf("encoded: decode: %w", err)
+		}
+		hv.Call([]reflect.Value{argPtr.Elem()})
+		return nil
+	}, nil
+}
@@ -0,0 +1,58 @@
+package encoded
+
+import (
+	"testing"
+
+	"github.com/wazwki/HTTPServerWithNATS/codec"
+)
+
+type testUpdate struct {
+	ID   string
+	Body string
+}
+
+func TestNewTypedDispatcherDecodesAndCallsHandler(t *testing.T) {
+	var got testUpdate
+	dispatch, err := newTypedDispatcher(codec.JSONCodec{}, func(u testUpdate) {
+		got = u
+	})
+	if err != nil {
+		t.Fatalf("newTypedDispatcher: %v", err)
+	}
+
+	data, err := codec.JSONCodec{}.Encode(testUpdate{ID: "1", Body: "hello"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	if err := dispatch(data); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+
+	want := testUpdate{ID: "1", Body: "hello"}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestNewTypedDispatcherRejectsBadHandlerShape(t *testing.T) {
+	if _, err := newTypedDispatcher(codec.JSONCodec{}, func() {}); err == nil {
+		t.Fatal("expected error for a handler with no arguments")
+	}
+	if _, err := newTypedDispatcher(codec.JSONCodec{}, "not a func"); err == nil {
+		t.Fatal("expected error for a non-func handler")
+	}
+}
+
+func TestNewTypedDispatcherReportsDecodeFailure(t *testing.T) {
+	dispatch, err := newTypedDispatcher(codec.JSONCodec{}, func(u testUpdate) {
+		t.Fatal("handler should not run on a decode failure")
+	})
+	if err != nil {
+		t.Fatalf("newTypedDispatcher: %v", err)
+	}
+
+	if err := dispatch([]byte("not json")); err == nil {
+		t.Fatal("expected a decode error")
+	}
+}
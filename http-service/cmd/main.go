@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/wazwki/HTTPServerWithNATS/natsconn"
+	"github.com/wazwki/HTTPServerWithNATS/natsrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultRPCTimeout bounds how long an HTTP request will wait for a backend
+// microservice to reply over NATS, absent an operator override.
+const defaultRPCTimeout = 5 * time.Second
+
+var tracer = otel.Tracer("github.com/wazwki/HTTPServerWithNATS/http-service")
+
+func main() {
+	rpcTimeout := flag.Duration("rpc-timeout", defaultRPCTimeout, "how long to wait for a backend reply over NATS before failing the request")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	nc, err := natsconn.Connect(nats.DefaultURL, natsconn.DefaultConfig(), logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer nc.Close()
+
+	client := natsrpc.NewClient(nc)
+
+	http.HandleFunc("/healthz", natsconn.HealthHandler())
+	http.HandleFunc("/readyz", natsconn.ReadyHandler(nc))
+	http.Handle("/metrics", promhttp.Handler())
+
+	http.HandleFunc("/updates", func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "POST /updates", trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, *rpcTimeout)
+		defer cancel()
+
+		resp, err := client.Call(ctx, "rpc.updates", body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.Write(resp)
+	})
+
+	log.Println("HTTP сервер запущен на :8080")
+	if err := http.ListenAndServe(":8080", nil); err != nil {
+		log.Fatal(err)
+	}
+}
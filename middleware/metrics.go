@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	messagesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nats_messages_total",
+		Help: "Messages handled per subject.",
+	}, []string{"subject"})
+
+	errorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nats_errors_total",
+		Help: "Handler panics per subject.",
+	}, []string{"subject"})
+
+	handleLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "nats_handle_duration_seconds",
+		Help: "Time spent in the message handler per subject.",
+	}, []string{"subject"})
+)
+
+func init() {
+	prometheus.MustRegister(messagesTotal, errorsTotal, handleLatency)
+}
+
+// Metrics records per-subject message counts, handler panics and latency.
+// Errors returned by an ErrHandler wrapped with ChainErr are not counted. It
+// re-panics after counting a panic, so put Recovery outside it in the
+// chain, e.g. Chain(handler, Recovery(), Metrics()).
+func Metrics() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, msg *nats.Msg) {
+			start := time.Now()
+			messagesTotal.WithLabelValues(msg.Subject).Inc()
+
+			defer func() {
+				handleLatency.WithLabelValues(msg.Subject).Observe(time.Since(start).Seconds())
+				if r := recover(); r != nil {
+					errorsTotal.WithLabelValues(msg.Subject).Inc()
+					panic(r)
+				}
+			}()
+
+			next(ctx, msg)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package middleware adds cross-cutting concerns — tracing, metrics, panic
+// recovery — around NATS publish and subscribe calls, the same way an HTTP
+// middleware chain wraps handlers.
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Handler processes a single message once all middleware has run.
+type Handler func(ctx context.Context, msg *nats.Msg)
+
+// Middleware wraps a Handler to add behavior before and/or after it runs.
+type Middleware func(next Handler) Handler
+
+// Chain composes mw in the order given, so the first middleware is the
+// outermost wrapper and runs first.
+func Chain(handler Handler, mw ...Middleware) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	return handler
+}
+
+// ErrHandler is a Handler variant that reports whether processing failed, so
+// callers with at-least-once semantics (e.g. stream.Consumer) can Nak and
+// redeliver the message instead of silently dropping it.
+type ErrHandler func(ctx context.Context, msg *nats.Msg) error
+
+// ChainErr runs handler through mw like Chain, preserving whatever error
+// handler returns so the caller can act on it. If handler panics, the panic
+// is recorded as an error before being re-panicked, so an outer Recovery
+// still stops it from crashing the process while ChainErr still reports the
+// failure instead of returning nil as if the message had succeeded.
+func ChainErr(handler ErrHandler, mw ...Middleware) ErrHandler {
+	var err error
+	wrapped := Chain(func(ctx context.Context, msg *nats.Msg) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("middleware: handler panic: %v", r)
+				panic(r)
+			}
+		}()
+		err = handler(ctx, msg)
+	}, mw...)
+
+	return func(ctx context.Context, msg *nats.Msg) error {
+		err = nil
+		wrapped(ctx, msg)
+		return err
+	}
+}
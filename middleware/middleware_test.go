@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+)
+
+func TestChainRunsMiddlewareOutermostFirst(t *testing.T) {
+	var order []string
+
+	mark := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, msg *nats.Msg) {
+				order = append(order, name+":before")
+				next(ctx, msg)
+				order = append(order, name+":after")
+			}
+		}
+	}
+
+	handler := Chain(func(ctx context.Context, msg *nats.Msg) {
+		order = append(order, "handler")
+	}, mark("outer"), mark("inner"))
+
+	handler(context.Background(), &nats.Msg{Subject: "test"})
+
+	want := []string{"outer:before", "inner:before", "handler", "inner:after", "outer:after"}
+	if len(order) != len(want) {
+		t.Fatalf("got %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("got %v, want %v", order, want)
+		}
+	}
+}
+
+func TestChainErrPropagatesHandlerError(t *testing.T) {
+	boom := errFixture("boom")
+
+	handle := ChainErr(func(ctx context.Context, msg *nats.Msg) error {
+		return boom
+	}, Recovery())
+
+	if err := handle(context.Background(), &nats.Msg{Subject: "test"}); err != boom {
+		t.Fatalf("got %v, want %v", err, boom)
+	}
+}
+
+func TestChainErrResetsBetweenCalls(t *testing.T) {
+	fail := true
+	handle := ChainErr(func(ctx context.Context, msg *nats.Msg) error {
+		if fail {
+			fail = false
+			return errFixture("first call fails")
+		}
+		return nil
+	}, Recovery())
+
+	if err := handle(context.Background(), &nats.Msg{Subject: "test"}); err == nil {
+		t.Fatal("expected error on first call")
+	}
+	if err := handle(context.Background(), &nats.Msg{Subject: "test"}); err != nil {
+		t.Fatalf("expected nil on second call, got %v", err)
+	}
+}
+
+func TestChainErrReportsErrorOnPanic(t *testing.T) {
+	handle := ChainErr(func(ctx context.Context, msg *nats.Msg) error {
+		panic("boom")
+	}, Recovery(), Metrics())
+
+	err := handle(context.Background(), &nats.Msg{Subject: "test"})
+	if err == nil {
+		t.Fatal("expected an error for a panicking handler, got nil")
+	}
+}
+
+type errFixture string
+
+func (e errFixture) Error() string { return string(e) }
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Recovery stops a panicking handler from taking down the subscriber
+// process, logging the recovered value instead.
+func Recovery() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, msg *nats.Msg) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("middleware: recovered panic handling %q: %v", msg.Subject, r)
+				}
+			}()
+			next(ctx, msg)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies this package's spans in trace backends.
+const tracerName = "github.com/wazwki/HTTPServerWithNATS/middleware"
+
+// headerCarrier adapts nats.Header to propagation.TextMapCarrier so W3C
+// traceparent headers can be extracted from and injected into NATS messages.
+type headerCarrier nats.Header
+
+func (c headerCarrier) Get(key string) string {
+	vals := nats.Header(c).Values(key)
+	if len(vals) == 0 {
+		return ""
+	}
+	return vals[0]
+}
+
+func (c headerCarrier) Set(key, value string) {
+	nats.Header(c).Set(key, value)
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Tracing extracts a W3C traceparent from the message headers (if present)
+// and starts a child span named after subject before calling next, so HTTP →
+// NATS → handler forms one continuous trace.
+func Tracing() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, msg *nats.Msg) {
+			ctx, span := StartSpanFromMsg(ctx, msg, trace.WithSpanKind(trace.SpanKindConsumer))
+			defer span.End()
+
+			next(ctx, msg)
+		}
+	}
+}
+
+// StartSpanFromMsg extracts any W3C traceparent carried in msg's headers and
+// starts a child span named after msg.Subject, continuing the same trace
+// across the NATS hop. Callers that aren't going through Chain/Tracing (e.g.
+// natsrpc's request-reply handlers) use this directly.
+func StartSpanFromMsg(ctx context.Context, msg *nats.Msg, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if msg.Header != nil {
+		ctx = propagation.TraceContext{}.Extract(ctx, headerCarrier(msg.Header))
+	}
+	return otel.Tracer(tracerName).Start(ctx, msg.Subject, opts...)
+}
+
+// InjectSpan writes the span context carried by ctx into msg's headers as a
+// W3C traceparent, so the receiving subscriber can continue the trace.
+func InjectSpan(ctx context.Context, msg *nats.Msg) {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	propagation.TraceContext{}.Inject(ctx, headerCarrier(msg.Header))
+}
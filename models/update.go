@@ -0,0 +1,12 @@
+// Package models holds domain types shared across the HTTP, producer and
+// consumer services.
+package models
+
+import "time"
+
+// Update is the payload published on the "updates" subject hierarchy.
+type Update struct {
+	ID        string    `json:"id"`
+	Body      string    `json:"body"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,57 @@
+// Package natsconn builds reconnect-safe NATS connections shared by all
+// three services, so a broker restart degrades gracefully instead of
+// killing the process, and emits structured logs for connection lifecycle
+// events.
+package natsconn
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Config tunes reconnect behavior. MaxReconnects of -1 retries forever.
+type Config struct {
+	MaxReconnects   int
+	ReconnectWait   time.Duration
+	ReconnectJitter time.Duration
+	PingInterval    time.Duration
+}
+
+// DefaultConfig retries forever with a modest backoff and jitter.
+func DefaultConfig() Config {
+	return Config{
+		MaxReconnects:   -1,
+		ReconnectWait:   2 * time.Second,
+		ReconnectJitter: 500 * time.Millisecond,
+		PingInterval:    20 * time.Second,
+	}
+}
+
+// Connect dials url with cfg applied and logs every disconnect, reconnect
+// and close through logger.
+func Connect(url string, cfg Config, logger *slog.Logger) (*nats.Conn, error) {
+	opts := []nats.Option{
+		nats.MaxReconnects(cfg.MaxReconnects),
+		nats.ReconnectWait(cfg.ReconnectWait),
+		nats.ReconnectJitter(cfg.ReconnectJitter, cfg.ReconnectJitter),
+		nats.PingInterval(cfg.PingInterval),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			logger.Warn("nats disconnected", "error", err)
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			logger.Info("nats reconnected", "url", nc.ConnectedUrl())
+		}),
+		nats.ClosedHandler(func(*nats.Conn) {
+			logger.Info("nats connection closed")
+		}),
+	}
+
+	nc, err := nats.Connect(url, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("natsconn: connect %q: %w", url, err)
+	}
+	return nc, nil
+}
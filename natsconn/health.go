@@ -0,0 +1,27 @@
+package natsconn
+
+import (
+	"net/http"
+
+	"github.com/nats-io/nats.go"
+)
+
+// HealthHandler always returns 200 once the process is up; it reports
+// liveness, not broker connectivity.
+func HealthHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// ReadyHandler returns 200 only while nc is connected, so orchestrators can
+// pull the instance out of rotation while NATS is unreachable.
+func ReadyHandler(nc *nats.Conn) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !nc.IsConnected() {
+			http.Error(w, "nats not connected", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,36 @@
+// Package natsrpc implements a thin request-reply layer on top of NATS core,
+// letting HTTP handlers call backend microservices synchronously instead of
+// only publishing fire-and-forget messages.
+package natsrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/wazwki/HTTPServerWithNATS/middleware"
+)
+
+// Client issues RPC calls over an existing NATS connection.
+type Client struct {
+	nc *nats.Conn
+}
+
+// NewClient wraps an established NATS connection for RPC calls.
+func NewClient(nc *nats.Conn) *Client {
+	return &Client{nc: nc}
+}
+
+// Call sends payload to subject and waits for a single reply, honoring ctx's
+// deadline/cancellation. The current span context, if any, is injected into
+// the request headers so the handling Server continues the same trace.
+func (c *Client) Call(ctx context.Context, subject string, payload []byte) ([]byte, error) {
+	req := &nats.Msg{Subject: subject, Data: payload}
+	middleware.InjectSpan(ctx, req)
+
+	msg, err := c.nc.RequestMsgWithContext(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("natsrpc: call %q: %w", subject, err)
+	}
+	return msg.Data, nil
+}
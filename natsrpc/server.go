@@ -0,0 +1,67 @@
+package natsrpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/nats-io/nats.go"
+	"github.com/wazwki/HTTPServerWithNATS/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HandlerFunc processes a single RPC request and returns the bytes to send
+// back to the caller.
+type HandlerFunc func(ctx context.Context, req []byte) ([]byte, error)
+
+// Server dispatches incoming NATS requests to registered handlers and
+// replies via msg.Respond.
+type Server struct {
+	nc   *nats.Conn
+	subs []*nats.Subscription
+}
+
+// NewServer wraps an established NATS connection for serving RPCs.
+func NewServer(nc *nats.Conn) *Server {
+	return &Server{nc: nc}
+}
+
+// Handle registers fn to answer requests published on subject. Each call
+// continues the caller's trace (see Client.Call) and recovers panics so one
+// bad request can't take the subscriber down.
+func (s *Server) Handle(subject string, fn HandlerFunc) error {
+	sub, err := s.nc.Subscribe(subject, func(msg *nats.Msg) {
+		ctx, span := middleware.StartSpanFromMsg(context.Background(), msg, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("natsrpc: handler for %q panicked: %v", subject, r)
+			}
+		}()
+
+		resp, err := fn(ctx, msg.Data)
+		if err != nil {
+			log.Printf("natsrpc: handler for %q failed: %v", subject, err)
+			return
+		}
+		if err := msg.Respond(resp); err != nil {
+			log.Printf("natsrpc: respond on %q failed: %v", subject, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("natsrpc: handle %q: %w", subject, err)
+	}
+	s.subs = append(s.subs, sub)
+	return nil
+}
+
+// Close unsubscribes all registered handlers.
+func (s *Server) Close() error {
+	for _, sub := range s.subs {
+		if err := sub.Unsubscribe(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
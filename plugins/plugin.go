@@ -0,0 +1,136 @@
+// Package plugins hosts WebAssembly guest modules that react to incoming
+// NATS messages without requiring the subscriber to be recompiled. Guests
+// are loaded with wazero and must export a handler function with the
+// signature func(dataPtr, dataLen uint32).
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/nats-io/nats.go"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Plugin is a single loaded WASM module bound to one exported handler.
+type Plugin struct {
+	path    string
+	handler string
+
+	runtime wazero.Runtime
+	module  api.Module
+	fn      api.Function
+}
+
+// Load compiles path and instantiates it, exposing the host ABI (log,
+// publish-reply, get-subject) to the guest and resolving its exported
+// handler function.
+func Load(ctx context.Context, nc *nats.Conn, path, handler string) (*Plugin, error) {
+	wasmBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("plugins: read %q: %w", path, err)
+	}
+
+	runtime := wazero.NewRuntime(ctx)
+
+	p := &Plugin{path: path, handler: handler, runtime: runtime}
+
+	_, err = runtime.NewHostModuleBuilder("env").
+		NewFunctionBuilder().
+		WithFunc(p.hostLog).
+		Export("host_log").
+		NewFunctionBuilder().
+		WithFunc(p.hostPublishReply(nc)).
+		Export("host_publish_reply").
+		NewFunctionBuilder().
+		WithFunc(p.hostGetSubject).
+		Export("host_get_subject").
+		Instantiate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("plugins: build host module: %w", err)
+	}
+
+	module, err := runtime.Instantiate(ctx, wasmBytes)
+	if err != nil {
+		return nil, fmt.Errorf("plugins: instantiate %q: %w", path, err)
+	}
+	p.module = module
+
+	fn := module.ExportedFunction(handler)
+	if fn == nil {
+		return nil, fmt.Errorf("plugins: %q does not export %q", path, handler)
+	}
+	p.fn = fn
+
+	return p, nil
+}
+
+// lastSubject stashes the subject of the message currently being dispatched
+// so the guest's host_get_subject call can read it back.
+var lastSubject string
+
+// Dispatch copies msg.Data into guest memory and invokes the plugin's
+// handler with (data_ptr, data_len).
+func (p *Plugin) Dispatch(ctx context.Context, msg *nats.Msg) error {
+	lastSubject = msg.Subject
+
+	mem := p.module.Memory()
+	const guestBufAddr = 1 << 16 // fixed scratch region past the guest's static data
+
+	if ok := mem.Write(guestBufAddr, msg.Data); !ok {
+		return fmt.Errorf("plugins: write %d bytes to guest memory failed", len(msg.Data))
+	}
+
+	_, err := p.fn.Call(ctx, guestBufAddr, uint64(len(msg.Data)))
+	if err != nil {
+		return fmt.Errorf("plugins: call %q: %w", p.handler, err)
+	}
+	return nil
+}
+
+// Close releases the underlying wazero runtime.
+func (p *Plugin) Close(ctx context.Context) error {
+	return p.runtime.Close(ctx)
+}
+
+func (p *Plugin) hostLog(ctx context.Context, m api.Module, ptr, length uint32) {
+	data, ok := m.Memory().Read(ptr, length)
+	if !ok {
+		log.Printf("plugins: %s: host_log: bad memory range", p.path)
+		return
+	}
+	log.Printf("plugins: %s: %s", p.path, string(data))
+}
+
+func (p *Plugin) hostPublishReply(nc *nats.Conn) func(ctx context.Context, m api.Module, subjectPtr, subjectLen, dataPtr, dataLen uint32) {
+	return func(ctx context.Context, m api.Module, subjectPtr, subjectLen, dataPtr, dataLen uint32) {
+		subject, ok := m.Memory().Read(subjectPtr, subjectLen)
+		if !ok {
+			return
+		}
+		data, ok := m.Memory().Read(dataPtr, dataLen)
+		if !ok {
+			return
+		}
+		if err := nc.Publish(string(subject), data); err != nil {
+			log.Printf("plugins: %s: host_publish_reply: %v", p.path, err)
+		}
+	}
+}
+
+func (p *Plugin) hostGetSubject(ctx context.Context, m api.Module, bufPtr, bufLen uint32) uint32 {
+	if ok := m.Memory().Write(bufPtr, []byte(lastSubject)[:min(int(bufLen), len(lastSubject))]); !ok {
+		return 0
+	}
+	return uint32(min(int(bufLen), len(lastSubject)))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
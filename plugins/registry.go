@@ -0,0 +1,114 @@
+package plugins
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// pollInterval controls how often the registry checks the plugin file's
+// mtime for changes.
+const pollInterval = time.Second
+
+// loadedPlugin pairs a Plugin with a count of its in-flight Dispatch calls,
+// so a reload can wait for those calls to finish before closing the plugin
+// out from under them.
+type loadedPlugin struct {
+	plugin *Plugin
+	wg     sync.WaitGroup
+}
+
+// Registry owns a single plugin and reloads it whenever its file on disk
+// changes. current is swapped under mu so Dispatch (called from NATS
+// subscription callbacks) and reload (called from Watch's ticker goroutine)
+// never race on the same pointer.
+type Registry struct {
+	nc      *nats.Conn
+	path    string
+	handler string
+
+	mu      sync.RWMutex
+	current *loadedPlugin
+	modTime time.Time
+}
+
+// NewRegistry loads path once and returns a registry that keeps it fresh.
+func NewRegistry(ctx context.Context, nc *nats.Conn, path, handler string) (*Registry, error) {
+	r := &Registry{nc: nc, path: path, handler: handler}
+	if err := r.reload(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Watch polls the plugin file for changes until ctx is canceled, reloading
+// it in place whenever its mtime advances.
+func (r *Registry) Watch(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(r.path)
+			if err != nil {
+				continue
+			}
+			if info.ModTime().After(r.modTime) {
+				if err := r.reload(ctx); err != nil {
+					log.Printf("plugins: reload %q: %v", r.path, err)
+				}
+			}
+		}
+	}
+}
+
+// reload loads a fresh copy of the plugin and swaps it in. The previous
+// plugin, if any, is closed only once its in-flight Dispatch calls have all
+// returned, so a reload never tears down a runtime a concurrent Dispatch is
+// still using.
+func (r *Registry) reload(ctx context.Context) error {
+	p, err := Load(ctx, r.nc, r.path, r.handler)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(r.path)
+	if err == nil {
+		r.modTime = info.ModTime()
+	}
+
+	lp := &loadedPlugin{plugin: p}
+
+	r.mu.Lock()
+	old := r.current
+	r.current = lp
+	r.mu.Unlock()
+
+	if old != nil {
+		go func() {
+			old.wg.Wait()
+			if err := old.plugin.Close(ctx); err != nil {
+				log.Printf("plugins: close %q: %v", r.path, err)
+			}
+		}()
+	}
+	log.Printf("plugins: loaded %q (handler %q)", r.path, r.handler)
+	return nil
+}
+
+// Dispatch forwards msg to the currently active plugin version.
+func (r *Registry) Dispatch(ctx context.Context, msg *nats.Msg) error {
+	r.mu.RLock()
+	lp := r.current
+	lp.wg.Add(1)
+	r.mu.RUnlock()
+
+	defer lp.wg.Done()
+	return lp.plugin.Dispatch(ctx, msg)
+}
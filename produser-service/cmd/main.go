@@ -1,22 +1,60 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/wazwki/HTTPServerWithNATS/codec"
+	"github.com/wazwki/HTTPServerWithNATS/encoded"
+	"github.com/wazwki/HTTPServerWithNATS/models"
+	"github.com/wazwki/HTTPServerWithNATS/natsconn"
+	"github.com/wazwki/HTTPServerWithNATS/stream"
 )
 
 func main() {
-	nc, err := nats.Connect(nats.DefaultURL)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	nc, err := natsconn.Connect(nats.DefaultURL, natsconn.DefaultConfig(), logger)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer nc.Close()
 
-	err = nc.Publish("updates", []byte("Hello, NATS!"))
+	js, err := nc.JetStream()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := stream.EnsureStream(js); err != nil {
+		log.Fatal(err)
+	}
+
+	update := models.Update{
+		ID:        "1",
+		Body:      "Hello, NATS!",
+		Timestamp: time.Now(),
+	}
+
+	data, err := codec.JSONCodec{}.Encode(update)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	publisher := stream.NewPublisher(js)
+	if err := publisher.PublishWithHeaders(context.Background(), "updates.created", data); err != nil {
+		log.Fatal(err)
+	}
+
+	// Durable delivery goes through the JetStream publisher above; this is a
+	// best-effort mirror for subscribers that only want the latest value,
+	// not replay (e.g. a live dashboard), so a missed message isn't fatal.
+	ec := encoded.NewEncodedConn(nc, codec.JSONCodec{})
+	if err := ec.PublishTyped("live.updates", update); err != nil {
+		log.Printf("live mirror publish failed: %v", err)
+	}
+
 	log.Println("Сообщение отправлено")
 }
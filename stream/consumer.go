@@ -0,0 +1,74 @@
+package stream
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/nats-io/nats.go"
+)
+
+// fetchBatch is how many messages each pull request asks JetStream for.
+const fetchBatch = 32
+
+// Consumer pulls messages from a durable JetStream consumer and dispatches
+// them to a handler, acking on success and nacking (triggering redelivery)
+// on failure.
+type Consumer struct {
+	sub *nats.Subscription
+}
+
+// NewDurableConsumer creates (or binds to) a durable pull consumer on subject
+// using cfg, resuming from the last acked sequence on restart.
+func NewDurableConsumer(js nats.JetStreamContext, subject string, cfg Config) (*Consumer, error) {
+	opts := []nats.SubOpt{
+		nats.Durable(cfg.Durable),
+		nats.AckWait(cfg.AckWait),
+		nats.MaxAckPending(cfg.MaxInFlight),
+		nats.ManualAck(),
+	}
+	switch cfg.AckPolicy {
+	case nats.AckAllPolicy:
+		opts = append(opts, nats.AckAll())
+	case nats.AckNonePolicy:
+		opts = append(opts, nats.AckNone())
+	default:
+		opts = append(opts, nats.AckExplicit())
+	}
+	if !cfg.ReplayFrom.IsZero() {
+		opts = append(opts, nats.StartTime(cfg.ReplayFrom))
+	}
+
+	sub, err := js.PullSubscribe(subject, cfg.Durable, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("stream: pull subscribe %q: %w", subject, err)
+	}
+	return &Consumer{sub: sub}, nil
+}
+
+// Run fetches messages in a loop and hands each to handler until the
+// subscription is drained or the process exits. A handler error nacks the
+// message so JetStream redelivers it.
+func (c *Consumer) Run(handler func(msg *nats.Msg) error) {
+	for {
+		msgs, err := c.sub.Fetch(fetchBatch)
+		if err != nil {
+			if err == nats.ErrTimeout {
+				continue
+			}
+			if !c.sub.IsValid() {
+				return
+			}
+			log.Printf("stream: fetch: %v", err)
+			continue
+		}
+
+		for _, msg := range msgs {
+			if err := handler(msg); err != nil {
+				log.Printf("stream: handler error, nacking: %v", err)
+				_ = msg.Nak()
+				continue
+			}
+			_ = msg.Ack()
+		}
+	}
+}
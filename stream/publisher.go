@@ -0,0 +1,48 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/wazwki/HTTPServerWithNATS/middleware"
+)
+
+// Publisher publishes to the stream asynchronously and tracks acknowledgment
+// futures so the caller can find out about publish failures.
+type Publisher struct {
+	js nats.JetStreamContext
+}
+
+// NewPublisher wraps js for async, acknowledged publishing.
+func NewPublisher(js nats.JetStreamContext) *Publisher {
+	return &Publisher{js: js}
+}
+
+// Publish sends data on subject and returns once JetStream has acknowledged
+// the message (or the ack wait has elapsed).
+func (p *Publisher) Publish(subject string, data []byte) error {
+	return p.PublishWithHeaders(context.Background(), subject, data)
+}
+
+// PublishWithHeaders behaves like Publish but also injects the span context
+// carried by ctx into the message headers, so a subscriber wrapped with
+// middleware.Tracing continues the same trace.
+func (p *Publisher) PublishWithHeaders(ctx context.Context, subject string, data []byte) error {
+	msg := &nats.Msg{Subject: subject, Data: data}
+	middleware.InjectSpan(ctx, msg)
+
+	future, err := p.js.PublishMsgAsync(msg)
+	if err != nil {
+		return fmt.Errorf("stream: publish async %q: %w", subject, err)
+	}
+
+	select {
+	case <-future.Ok():
+		return nil
+	case err := <-future.Err():
+		return fmt.Errorf("stream: publish %q: %w", subject, err)
+	case <-ctx.Done():
+		return fmt.Errorf("stream: publish %q: %w", subject, ctx.Err())
+	}
+}
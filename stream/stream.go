@@ -0,0 +1,58 @@
+// Package stream provisions and consumes a JetStream-backed "updates.*"
+// subject hierarchy, giving the producer/consumer pair at-least-once
+// delivery and replay instead of the fire-and-forget semantics of core NATS.
+package stream
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// StreamName is the JetStream stream backing the updates.* hierarchy.
+const StreamName = "UPDATES"
+
+// Subjects are the subjects captured by the stream. Request-reply traffic
+// lives under the separate "rpc.*" hierarchy (see natsrpc) so RPC calls are
+// never captured and replayed as durable update events.
+var Subjects = []string{"updates.*"}
+
+// Config tunes durable consumption of the stream.
+type Config struct {
+	Durable     string        // durable consumer name, survives restarts
+	AckPolicy   nats.AckPolicy
+	AckWait     time.Duration
+	MaxInFlight int       // MaxAckPending
+	ReplayFrom  time.Time // zero value replays from the stream's start
+}
+
+// DefaultConfig returns sane defaults for a durable pull consumer named durable.
+func DefaultConfig(durable string) Config {
+	return Config{
+		Durable:     durable,
+		AckPolicy:   nats.AckExplicitPolicy,
+		AckWait:     30 * time.Second,
+		MaxInFlight: 64,
+	}
+}
+
+// EnsureStream creates the UPDATES stream if it does not already exist.
+func EnsureStream(js nats.JetStreamContext) error {
+	_, err := js.StreamInfo(StreamName)
+	if err == nil {
+		return nil
+	}
+	if err != nats.ErrStreamNotFound {
+		return fmt.Errorf("stream: lookup %q: %w", StreamName, err)
+	}
+
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:     StreamName,
+		Subjects: Subjects,
+	})
+	if err != nil {
+		return fmt.Errorf("stream: create %q: %w", StreamName, err)
+	}
+	return nil
+}